@@ -0,0 +1,279 @@
+// Package lsp implements a minimal JSON-RPC 2.0 client for the Language
+// Server Protocol, used by edit to drive per-language servers for
+// completion, hover tips and diagnostics.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// request is an outgoing JSON-RPC request or notification. Notifications
+// omit ID.
+type request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// response is an incoming JSON-RPC message: a reply to one of our requests
+// if ID is set, or a server-initiated notification otherwise.
+type response struct {
+	ID     int             `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("lsp: %s (%d)", e.Message, e.Code)
+}
+
+// Handler receives notifications pushed by the server, keyed by method
+// (e.g. "textDocument/publishDiagnostics").
+type Handler func(method string, params json.RawMessage)
+
+// Client is a connection to a single language server, speaking JSON-RPC 2.0
+// framed with Content-Length headers over its stdin/stdout.
+type Client struct {
+	cmd      *exec.Cmd
+	w        io.Writer
+	onNotify Handler
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan response
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Start launches command as a language server subprocess and begins
+// reading its responses and notifications. onNotify is called for every
+// server-initiated notification, including publishDiagnostics.
+func Start(name string, args []string, onNotify Handler) (*Client, error) {
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return newClient(stdin, stdout, cmd, onNotify), nil
+}
+
+// newClient wires up a Client around an already-connected read/write pair,
+// so both Start and in-process fakes (used in tests) share one code path.
+func newClient(w io.Writer, r io.Reader, cmd *exec.Cmd, onNotify Handler) *Client {
+	c := &Client{
+		cmd: cmd, w: w, onNotify: onNotify,
+		nextID:  1, // 0 is reserved so requests are never mistaken for notifications under "id,omitempty"
+		pending: make(map[int]chan response),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop(bufio.NewReader(r))
+	return c
+}
+
+// Connect wires up a Client around an already-connected read/write pair,
+// for servers that are not launched as a subprocess (e.g. the in-process
+// fake server used in tests).
+func Connect(w io.Writer, r io.Reader, onNotify Handler) *Client {
+	return newClient(w, r, nil, onNotify)
+}
+
+func (c *Client) readLoop(r *bufio.Reader) {
+	defer close(c.closed)
+	for {
+		msg, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		var resp response
+		if err := json.Unmarshal(msg, &resp); err != nil {
+			continue
+		}
+		if resp.Method != "" {
+			if c.onNotify != nil {
+				c.onNotify(resp.Method, resp.Params)
+			}
+			continue
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[resp.ID]
+		if ok {
+			delete(c.pending, resp.ID)
+		}
+		c.mu.Unlock()
+		if ok {
+			ch <- resp
+		}
+	}
+}
+
+// readFrame reads one "Content-Length: N\r\n\r\n<N bytes>" frame.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "Content-Length:") {
+			n, err := strconv.Atoi(strings.TrimSpace(line[len("Content-Length:"):]))
+			if err != nil {
+				return nil, err
+			}
+			length = n
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("lsp: frame missing Content-Length header")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func writeFrame(w io.Writer, body []byte) error {
+	_, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+// call sends a request and blocks for its response, honoring ctx
+// cancellation by firing $/cancelRequest and returning ctx.Err().
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	c.mu.Lock()
+	id := c.nextID
+	c.nextID++
+	ch := make(chan response, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	if err := c.send(request{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result == nil || resp.Result == nil {
+			return nil
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		c.notify("$/cancelRequest", struct {
+			ID int `json:"id"`
+		}{id})
+		return ctx.Err()
+	case <-c.closed:
+		return io.ErrClosedPipe
+	}
+}
+
+// notify sends a one-way notification; the server does not reply.
+func (c *Client) notify(method string, params interface{}) error {
+	return c.send(request{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (c *Client) send(req request) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return writeFrame(c.w, body)
+}
+
+// Initialize performs the initialize/initialized handshake against
+// rootURI.
+func (c *Client) Initialize(ctx context.Context, rootURI string) error {
+	params := struct {
+		RootURI string `json:"rootUri"`
+	}{rootURI}
+	if err := c.call(ctx, "initialize", params, nil); err != nil {
+		return err
+	}
+	return c.notify("initialized", struct{}{})
+}
+
+// Shutdown requests a graceful shutdown, sends exit, and reaps the child
+// process if one was started.
+func (c *Client) Shutdown(ctx context.Context) error {
+	callErr := c.call(ctx, "shutdown", nil, nil)
+	notifyErr := c.notify("exit", nil)
+	if c.cmd != nil {
+		c.cmd.Wait()
+	}
+	if callErr != nil {
+		return callErr
+	}
+	return notifyErr
+}
+
+// DidOpen notifies the server that doc is now open.
+func (c *Client) DidOpen(doc TextDocumentItem) error {
+	return c.notify("textDocument/didOpen", struct {
+		TextDocument TextDocumentItem `json:"textDocument"`
+	}{doc})
+}
+
+// DidChange sends an incremental (or, with a nil Range, whole-document)
+// update for doc at the given version.
+func (c *Client) DidChange(doc VersionedTextDocumentIdentifier, changes []TextDocumentContentChangeEvent) error {
+	return c.notify("textDocument/didChange", struct {
+		TextDocument   VersionedTextDocumentIdentifier   `json:"textDocument"`
+		ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+	}{doc, changes})
+}
+
+// Completion requests completion candidates at pos in uri.
+func (c *Client) Completion(ctx context.Context, uri string, pos Position) (CompletionList, error) {
+	var params TextDocumentPositionParams
+	params.TextDocument.URI = uri
+	params.Position = pos
+
+	var list CompletionList
+	err := c.call(ctx, "textDocument/completion", params, &list)
+	return list, err
+}
+
+// Hover requests hover information at pos in uri.
+func (c *Client) Hover(ctx context.Context, uri string, pos Position) (Hover, error) {
+	var params TextDocumentPositionParams
+	params.TextDocument.URI = uri
+	params.Position = pos
+
+	var h Hover
+	err := c.call(ctx, "textDocument/hover", params, &h)
+	return h, err
+}
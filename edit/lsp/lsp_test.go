@@ -0,0 +1,84 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeServer is a trivial in-process language server: it reads frames off
+// in, answers a fixed set of methods, and writes frames to out.
+type fakeServer struct {
+	in  io.Reader
+	out io.Writer
+}
+
+func (s *fakeServer) serve() {
+	r := bufio.NewReader(s.in)
+	for {
+		body, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		var req request
+		json.Unmarshal(body, &req)
+		if req.ID == 0 && req.Method != "initialized" && req.Method != "exit" {
+			// Notification we don't care to reply to.
+			continue
+		}
+		switch req.Method {
+		case "initialize":
+			writeFrame(s.out, mustMarshal(response{ID: req.ID, Result: mustMarshal(struct{}{})}))
+		case "textDocument/completion":
+			list := CompletionList{Items: []CompletionItem{{Label: "foo"}, {Label: "foobar"}}}
+			writeFrame(s.out, mustMarshal(response{ID: req.ID, Result: mustMarshal(list)}))
+		case "shutdown":
+			writeFrame(s.out, mustMarshal(response{ID: req.ID, Result: mustMarshal(struct{}{})}))
+		case "exit":
+			return
+		}
+	}
+}
+
+func mustMarshal(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestClientCompletion(t *testing.T) {
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+
+	server := &fakeServer{in: serverIn, out: serverOut}
+	go server.serve()
+
+	var notified []string
+	c := Connect(clientOut, clientIn, func(method string, _ json.RawMessage) {
+		notified = append(notified, method)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := c.Initialize(ctx, "file:///tmp/project"); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	list, err := c.Completion(ctx, "file:///tmp/project/main.elv", Position{Line: 0, Character: 3})
+	if err != nil {
+		t.Fatalf("Completion: %v", err)
+	}
+	if len(list.Items) != 2 || list.Items[0].Label != "foo" || list.Items[1].Label != "foobar" {
+		t.Errorf("Completion returned %+v, want [foo foobar]", list.Items)
+	}
+
+	if err := c.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package lsp
+
+// This file holds the subset of the Language Server Protocol's wire types
+// that the client actually uses. Field names follow Go convention; their
+// json tags carry the protocol's spelling so they can be (de)serialized
+// directly.
+
+// Position is a zero-based line/character offset, as used throughout LSP.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span between two positions.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// TextEdit replaces the text in Range with NewText.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// TextDocumentItem identifies an open document, sent with didOpen.
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+// VersionedTextDocumentIdentifier identifies a document at a specific
+// version, sent with didChange.
+type VersionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+}
+
+// TextDocumentContentChangeEvent describes one incremental change. When
+// Range is nil, Text replaces the whole document.
+type TextDocumentContentChangeEvent struct {
+	Range *Range `json:"range,omitempty"`
+	Text  string `json:"text"`
+}
+
+// TextDocumentPositionParams is the common shape of completion/hover
+// requests: which document, and where in it.
+type TextDocumentPositionParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position Position `json:"position"`
+}
+
+// CompletionItem is one candidate returned by textDocument/completion.
+type CompletionItem struct {
+	Label               string     `json:"label"`
+	Detail              string     `json:"detail,omitempty"`
+	Documentation       string     `json:"documentation,omitempty"`
+	InsertText          string     `json:"insertText,omitempty"`
+	TextEdit            *TextEdit  `json:"textEdit,omitempty"`
+	AdditionalTextEdits []TextEdit `json:"additionalTextEdits,omitempty"`
+}
+
+// CompletionList is the result of textDocument/completion.
+type CompletionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []CompletionItem `json:"items"`
+}
+
+// Hover is the result of textDocument/hover.
+type Hover struct {
+	Contents string `json:"contents"`
+	Range    *Range `json:"range,omitempty"`
+}
+
+// DiagnosticSeverity mirrors the protocol's 1-4 severity levels.
+type DiagnosticSeverity int
+
+// Severity levels, most to least severe.
+const (
+	SeverityError DiagnosticSeverity = iota + 1
+	SeverityWarning
+	SeverityInformation
+	SeverityHint
+)
+
+// Diagnostic is one issue reported against a range of a document.
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Message  string             `json:"message"`
+}
+
+// PublishDiagnosticsParams is the payload of a textDocument/publishDiagnostics
+// notification, pushed by the server whenever its view of a document's
+// diagnostics changes.
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
@@ -0,0 +1,295 @@
+package edit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/xiaq/elvish/edit/lsp"
+	"gopkg.in/yaml.v2"
+)
+
+// serverConfig describes one configured language server, as loaded from a
+// servers.yaml-style file mapping file extensions to a command to launch.
+type serverConfig struct {
+	Name    string   `yaml:"name"`
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+	RootURI string   `yaml:"rootUri"`
+}
+
+// lspConfig maps a file extension (without the leading dot) to the server
+// responsible for it.
+type lspConfig map[string]serverConfig
+
+// loadLSPConfig reads a YAML file listing language servers, keyed by the
+// file extensions they handle.
+func loadLSPConfig(path string) (lspConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg lspConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// lspManager owns one lsp.Client per configured server, starting each
+// lazily on first use and reusing it for subsequent buffers of the same
+// extension. It also tracks per-document versions for didChange and the
+// cancel func of whichever completion request is currently outstanding, so
+// a fresh keystroke can invalidate it.
+type lspManager struct {
+	config lspConfig
+
+	mu            sync.Mutex
+	clients       map[string]*lsp.Client // keyed by extension
+	versions      map[string]int         // keyed by document URI
+	cancelPending func()
+
+	// diagnostics holds the most recent publishDiagnostics tips per
+	// document URI, decoded by handleNotification; diagnosticTipsFor reads
+	// them back out.
+	diagnostics map[string][]tip
+}
+
+func newLSPManager(config lspConfig) *lspManager {
+	return &lspManager{
+		config:   config,
+		clients:  make(map[string]*lsp.Client),
+		versions: make(map[string]int),
+	}
+}
+
+// clientFor returns the running client for name's extension, starting its
+// server and performing the initialize handshake if this is the first
+// request for that extension.
+func (m *lspManager) clientFor(name string) (*lsp.Client, error) {
+	ext := strings.TrimPrefix(filepath.Ext(name), ".")
+	sc, ok := m.config[ext]
+	if !ok {
+		return nil, fmt.Errorf("lsp: no server configured for %q files", ext)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.clients[ext]; ok {
+		return c, nil
+	}
+
+	c, err := lsp.Start(sc.Command, sc.Args, func(method string, params []byte) {
+		m.handleNotification(ext, method, params)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Initialize(context.Background(), sc.RootURI); err != nil {
+		return nil, err
+	}
+	m.clients[ext] = c
+	return c, nil
+}
+
+// open notifies the server responsible for name that uri is now open,
+// seeding its version counter at 1.
+func (m *lspManager) open(name, uri, languageID, text string) error {
+	c, err := m.clientFor(name)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.versions[uri] = 1
+	m.mu.Unlock()
+	return c.DidOpen(lsp.TextDocumentItem{URI: uri, LanguageID: languageID, Version: 1, Text: text})
+}
+
+// change sends the new full contents of uri to its server, bumping its
+// version. Called on every buffer edit; elvish buffers are small enough
+// that whole-document sync is simpler than tracking incremental ranges.
+func (m *lspManager) change(name, uri, text string) error {
+	c, err := m.clientFor(name)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.versions[uri]++
+	v := m.versions[uri]
+	m.mu.Unlock()
+	return c.DidChange(
+		lsp.VersionedTextDocumentIdentifier{URI: uri, Version: v},
+		[]lsp.TextDocumentContentChangeEvent{{Text: text}})
+}
+
+// completeAt cancels whatever completion request is still outstanding
+// (the user has since typed another keystroke, making it stale) and
+// issues a new one for uri at pos.
+func (m *lspManager) completeAt(name, uri string, pos lsp.Position) (lsp.CompletionList, error) {
+	c, err := m.clientFor(name)
+	if err != nil {
+		return lsp.CompletionList{}, err
+	}
+
+	m.mu.Lock()
+	if m.cancelPending != nil {
+		m.cancelPending()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelPending = cancel
+	m.mu.Unlock()
+	defer cancel()
+
+	return c.Completion(ctx, uri, pos)
+}
+
+// handleNotification decodes the server-pushed notifications this manager
+// understands -- currently just textDocument/publishDiagnostics -- and
+// stashes the resulting tips for diagnosticTipsFor to read back. Unknown
+// methods and malformed params are ignored.
+//
+// TODO: this only updates the manager's own state. Actually pushing fresh
+// tips into a live bs.tips as they arrive needs a reference to the running
+// editorState, which this package doesn't have; that wiring belongs in the
+// command layer that registers this manager on an Editor.
+func (m *lspManager) handleNotification(ext, method string, params []byte) {
+	_ = ext
+	if method != "textDocument/publishDiagnostics" {
+		return
+	}
+	var p lsp.PublishDiagnosticsParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return
+	}
+	tips := diagnosticTips(p)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.diagnostics == nil {
+		m.diagnostics = make(map[string][]tip)
+	}
+	m.diagnostics[p.URI] = tips
+}
+
+// diagnosticTipsFor returns the most recent tips handleNotification
+// decoded for uri's diagnostics, or nil if none have arrived yet.
+func (m *lspManager) diagnosticTipsFor(uri string) []tip {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.diagnostics[uri]
+}
+
+// status summarizes the manager's running servers, for the ":lsp" command.
+func (m *lspManager) status() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.clients) == 0 {
+		return "no language servers running"
+	}
+	exts := make([]string, 0, len(m.clients))
+	for ext := range m.clients {
+		exts = append(exts, ext)
+	}
+	return "running: " + strings.Join(exts, ", ")
+}
+
+// applyLSPCompletions fills comp.candidates from an LSP completion
+// response. Text is taken from, in order of preference, TextEdit.NewText
+// (the server's own authoritative replacement text), InsertText, then
+// Label. TextEdit's Range itself isn't applied -- splicing it into the
+// buffer rather than appending a candidate is the completion engine's
+// job -- and neither are AdditionalTextEdits (used by servers that want to
+// touch text outside the completed word, e.g. auto-imports), since that
+// needs the same buffer-splicing support; instead their count is folded
+// into the candidate's detail so they're visible rather than silently
+// dropped.
+func applyLSPCompletions(comp *completion, list lsp.CompletionList) {
+	for _, item := range list.Items {
+		text := item.Label
+		if item.InsertText != "" {
+			text = item.InsertText
+		}
+		if item.TextEdit != nil {
+			text = item.TextEdit.NewText
+		}
+
+		detail := item.Detail
+		if n := len(item.AdditionalTextEdits); n > 0 {
+			note := fmt.Sprintf("+%d edit", n)
+			if n != 1 {
+				note += "s"
+			}
+			if detail != "" {
+				detail += " "
+			}
+			detail += note
+		}
+
+		comp.candidates = append(comp.candidates, candidate{
+			text:   text,
+			detail: detail,
+			doc:    item.Documentation,
+			parts:  []part{{text: text, completed: true}},
+		})
+	}
+}
+
+// lspHoverTip formats an LSP hover result the way other tips are rendered
+// (a single line, joined alongside existing tips in bs.tips).
+func lspHoverTip(h lsp.Hover) tip {
+	return tip{text: strings.Replace(h.Contents, "\n", " ", -1), attr: attrForTip}
+}
+
+// diagnosticSeverityTag renders a diagnostic's severity as the short tag
+// it's prefixed with in a tip's text, alongside the per-severity attr
+// diagnosticSeverityAttr assigns it.
+func diagnosticSeverityTag(sev lsp.DiagnosticSeverity) string {
+	switch sev {
+	case lsp.SeverityError:
+		return "error"
+	case lsp.SeverityWarning:
+		return "warning"
+	case lsp.SeverityInformation:
+		return "info"
+	default:
+		return "hint"
+	}
+}
+
+// diagnosticSeverityAttr maps a diagnostic's severity to the attr its tip
+// renders with, so e.g. an error stands out from a hint instead of every
+// diagnostic sharing attrForTip.
+func diagnosticSeverityAttr(sev lsp.DiagnosticSeverity) string {
+	switch sev {
+	case lsp.SeverityError:
+		return attrForTipError
+	case lsp.SeverityWarning:
+		return attrForTipWarning
+	case lsp.SeverityInformation:
+		return attrForTipInfo
+	default:
+		return attrForTipHint
+	}
+}
+
+// diagnosticTips formats a publishDiagnostics payload as tip lines, sorted
+// most severe first (ties keep the server's original order), for
+// appending to bs.tips.
+func diagnosticTips(params lsp.PublishDiagnosticsParams) []tip {
+	diags := append([]lsp.Diagnostic(nil), params.Diagnostics...)
+	sort.SliceStable(diags, func(i, j int) bool { return diags[i].Severity < diags[j].Severity })
+
+	tips := make([]tip, len(diags))
+	for i, d := range diags {
+		tips[i] = tip{
+			text: fmt.Sprintf("[%s] %s", diagnosticSeverityTag(d.Severity), d.Message),
+			attr: diagnosticSeverityAttr(d.Severity),
+		}
+	}
+	return tips
+}
@@ -0,0 +1,134 @@
+package edit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xiaq/elvish/edit/lsp"
+)
+
+func TestApplyLSPCompletionsPrefersInsertText(t *testing.T) {
+	comp := &completion{}
+	list := lsp.CompletionList{Items: []lsp.CompletionItem{
+		{Label: "foo"},
+		{Label: "bar", InsertText: "barInsert"},
+	}}
+	applyLSPCompletions(comp, list)
+
+	if len(comp.candidates) != 2 {
+		t.Fatalf("got %d candidates, want 2", len(comp.candidates))
+	}
+	if comp.candidates[0].text != "foo" {
+		t.Errorf("candidates[0].text = %q, want %q", comp.candidates[0].text, "foo")
+	}
+	if comp.candidates[1].text != "barInsert" {
+		t.Errorf("candidates[1].text = %q, want %q", comp.candidates[1].text, "barInsert")
+	}
+}
+
+func TestApplyLSPCompletionsPrefersTextEdit(t *testing.T) {
+	comp := &completion{}
+	list := lsp.CompletionList{Items: []lsp.CompletionItem{
+		{
+			Label:      "foo",
+			InsertText: "fooInsert",
+			Detail:     "func()",
+			TextEdit:   &lsp.TextEdit{NewText: "fooEdit"},
+			AdditionalTextEdits: []lsp.TextEdit{
+				{NewText: "import foo"},
+			},
+		},
+	}}
+	applyLSPCompletions(comp, list)
+
+	if got, want := comp.candidates[0].text, "fooEdit"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+	if got, want := comp.candidates[0].detail, "func() +1 edit"; got != want {
+		t.Errorf("detail = %q, want %q", got, want)
+	}
+}
+
+func TestHandleNotificationStoresDiagnosticTips(t *testing.T) {
+	m := newLSPManager(lspConfig{})
+	params, err := json.Marshal(lsp.PublishDiagnosticsParams{
+		URI: "file:///a.elv",
+		Diagnostics: []lsp.Diagnostic{
+			{Severity: lsp.SeverityError, Message: "undefined variable"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.handleNotification("elv", "textDocument/publishDiagnostics", params)
+
+	got := m.diagnosticTipsFor("file:///a.elv")
+	want := tip{text: "[error] undefined variable", attr: attrForTipError}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("diagnosticTipsFor = %v, want [%v]", got, want)
+	}
+
+	if got := m.diagnosticTipsFor("file:///other.elv"); got != nil {
+		t.Errorf("diagnosticTipsFor for unknown uri = %v, want nil", got)
+	}
+}
+
+func TestHandleNotificationIgnoresOtherMethods(t *testing.T) {
+	m := newLSPManager(lspConfig{})
+	m.handleNotification("elv", "window/logMessage", []byte(`{"message":"hi"}`))
+
+	if got := m.diagnosticTipsFor("file:///a.elv"); got != nil {
+		t.Errorf("diagnosticTipsFor = %v, want nil", got)
+	}
+}
+
+func TestDiagnosticTips(t *testing.T) {
+	params := lsp.PublishDiagnosticsParams{Diagnostics: []lsp.Diagnostic{
+		{Severity: lsp.SeverityError, Message: "undefined variable"},
+		{Severity: lsp.SeverityHint, Message: "consider renaming"},
+	}}
+	tips := diagnosticTips(params)
+
+	want := []tip{
+		{text: "[error] undefined variable", attr: attrForTipError},
+		{text: "[hint] consider renaming", attr: attrForTipHint},
+	}
+	if len(tips) != len(want) {
+		t.Fatalf("got %d tips, want %d", len(tips), len(want))
+	}
+	for i := range want {
+		if tips[i] != want[i] {
+			t.Errorf("tips[%d] = %v, want %v", i, tips[i], want[i])
+		}
+	}
+}
+
+func TestDiagnosticTipsSortsBySeverity(t *testing.T) {
+	// The server is free to send diagnostics in any order (typically
+	// position-sorted); diagnosticTips must reorder them most severe
+	// first rather than merely passing them through.
+	params := lsp.PublishDiagnosticsParams{Diagnostics: []lsp.Diagnostic{
+		{Severity: lsp.SeverityHint, Message: "consider renaming"},
+		{Severity: lsp.SeverityWarning, Message: "unused import"},
+		{Severity: lsp.SeverityError, Message: "undefined variable"},
+	}}
+	tips := diagnosticTips(params)
+
+	want := []string{"[error] undefined variable", "[warning] unused import", "[hint] consider renaming"}
+	if len(tips) != len(want) {
+		t.Fatalf("got %d tips, want %d", len(tips), len(want))
+	}
+	for i := range want {
+		if tips[i].text != want[i] {
+			t.Errorf("tips[%d].text = %q, want %q", i, tips[i].text, want[i])
+		}
+	}
+}
+
+func TestLSPManagerStatus(t *testing.T) {
+	m := newLSPManager(lspConfig{})
+	if got, want := m.status(), "no language servers running"; got != want {
+		t.Errorf("status() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package edit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// navColumn is one column of the ranger-style navigation pane: the
+// directory at path, its entries, and which one is selected.
+type navColumn struct {
+	path     string
+	names    []string
+	selected int
+}
+
+// navPreviewMaxLines caps how many lines of a regular file (or entries of
+// a directory listing) the preview column shows.
+const navPreviewMaxLines = 100
+
+// navPreviewMaxBytes caps how much of a regular file is read for the
+// preview, so a huge file doesn't stall refresh.
+const navPreviewMaxBytes = 64 * 1024
+
+// navMaxMinColWidth caps the minimum width navColumnWidths derives from
+// the longest filename, so one long name doesn't dominate the layout.
+const navMaxMinColWidth = 30
+
+// navigation holds the three-pane file navigator state: the parent
+// directory, the current directory, and a lazily computed, cached preview
+// of whichever entry is currently selected in current.
+type navigation struct {
+	parent, current *navColumn
+
+	previewFor   string // full path the cached preview was built for
+	previewLines []string
+}
+
+// preview returns the preview lines for the entry currently selected in
+// current, computing and caching them the first time a given selection is
+// rendered. It runs on the render path: refresh calls this, a cache hit
+// (the common case, since most keystrokes don't move the selection) is
+// O(1), and a change of selection re-reads from disk synchronously within
+// that refresh, capped at navPreviewMaxBytes.
+func (nav *navigation) preview() []string {
+	if nav.current == nil || nav.current.selected < 0 || nav.current.selected >= len(nav.current.names) {
+		nav.previewFor, nav.previewLines = "", nil
+		return nil
+	}
+	path := filepath.Join(nav.current.path, nav.current.names[nav.current.selected])
+	if path == nav.previewFor {
+		return nav.previewLines
+	}
+	nav.previewFor = path
+	nav.previewLines = readPreview(path)
+	return nav.previewLines
+}
+
+// readPreview renders a size-capped preview of path: a directory listing
+// if it's a directory, otherwise its first lines decoded as UTF-8 with
+// unprintable runes replaced.
+func readPreview(path string) []string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if info.IsDir() {
+		return previewDir(path)
+	}
+	return previewFile(path)
+}
+
+// previewDir lists path's entries, the same data a navColumn for it would
+// show if the user descended into it.
+func previewDir(path string) []string {
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name()
+	}
+	sort.Strings(names)
+	if len(names) > navPreviewMaxLines {
+		names = names[:navPreviewMaxLines]
+	}
+	return names
+}
+
+// previewFile reads up to navPreviewMaxBytes of path and returns its
+// first navPreviewMaxLines lines, sanitized for display.
+func previewFile(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	defer f.Close()
+
+	buf := make([]byte, navPreviewMaxBytes)
+	n, err := f.Read(buf)
+	if err != nil && n == 0 {
+		return []string{err.Error()}
+	}
+	lines := strings.Split(sanitizeForDisplay(string(buf[:n])), "\n")
+	if len(lines) > navPreviewMaxLines {
+		lines = lines[:navPreviewMaxLines]
+	}
+	return lines
+}
+
+// sanitizeForDisplay replaces runes that aren't printable UTF-8 (besides
+// newline) with U+FFFD, so a binary or non-UTF-8 file doesn't corrupt the
+// terminal.
+func sanitizeForDisplay(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '\n' || unicode.IsPrint(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(unicode.ReplacementChar)
+		}
+	}
+	return b.String()
+}
+
+// navColumnWidths allocates avail columns of screen width across ratios
+// (parent/current/preview, ranger-style), giving each column at least
+// minWidth before applying the ratio split to whatever remains.
+func navColumnWidths(ratios []int, avail, minWidth int) []int {
+	widths := make([]int, len(ratios))
+	total := 0
+	for _, r := range ratios {
+		total += r
+	}
+	if total == 0 || avail <= 0 {
+		return widths
+	}
+
+	reserved := minWidth * len(ratios)
+	if reserved > avail {
+		// Not enough room to give every column minWidth; fall back to a
+		// plain ratio split of avail so the columns never overflow the
+		// terminal width, even though some then land below minWidth.
+		for i, r := range ratios {
+			widths[i] = avail * r / total
+		}
+		return widths
+	}
+
+	extra := avail - reserved
+	for i, r := range ratios {
+		widths[i] = minWidth + extra*r/total
+	}
+	return widths
+}
+
+// longestName returns the wcwidth of the longest entry across cols,
+// clamped to max; it's used as the minimum width navColumnWidths gives
+// each column.
+func longestName(max int, cols ...*navColumn) int {
+	longest := 0
+	for _, col := range cols {
+		if col == nil {
+			continue
+		}
+		for _, name := range col.names {
+			if w := wcwidths(name); w > longest {
+				longest = w
+			}
+		}
+	}
+	if longest > max {
+		return max
+	}
+	return longest
+}
@@ -0,0 +1,109 @@
+package edit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNavColumnWidths(t *testing.T) {
+	widths := navColumnWidths([]int{1, 2, 3}, 60, 5)
+	if len(widths) != 3 {
+		t.Fatalf("got %d widths, want 3", len(widths))
+	}
+	sum := widths[0] + widths[1] + widths[2]
+	if sum > 60 || sum < 60-len(widths) {
+		t.Errorf("widths sum to %d, want close to 60", sum)
+	}
+	if widths[0] >= widths[1] || widths[1] >= widths[2] {
+		t.Errorf("widths = %v, want increasing with the 1:2:3 ratio", widths)
+	}
+}
+
+func TestNavColumnWidthsNeverExceedsAvail(t *testing.T) {
+	// Not enough room to honor minWidth for every column; the ratio split
+	// of avail wins so the columns never overflow the terminal width.
+	widths := navColumnWidths([]int{1, 2, 3}, 10, 10)
+	sum := 0
+	for _, w := range widths {
+		sum += w
+	}
+	if sum > 10 {
+		t.Errorf("widths = %v, sum %d exceeds avail 10", widths, sum)
+	}
+}
+
+func TestNavColumnWidthsRespectsMinWidth(t *testing.T) {
+	// Enough room for minWidth on every column; the ratio split only
+	// applies to the extra space beyond that.
+	widths := navColumnWidths([]int{1, 2, 3}, 60, 5)
+	for i, w := range widths {
+		if w < 5 {
+			t.Errorf("widths[%d] = %d, want >= minWidth 5", i, w)
+		}
+	}
+}
+
+func TestLongestNameClamps(t *testing.T) {
+	col := &navColumn{names: []string{"a", "a very long filename indeed"}}
+	if got := longestName(10, col); got != 10 {
+		t.Errorf("longestName = %d, want clamped to 10", got)
+	}
+	if got := longestName(100, col); got != wcwidths("a very long filename indeed") {
+		t.Errorf("longestName = %d, want unclamped longest", got)
+	}
+}
+
+func TestSanitizeForDisplay(t *testing.T) {
+	got := sanitizeForDisplay("ok\x00line\nnext")
+	want := "ok�line\nnext"
+	if got != want {
+		t.Errorf("sanitizeForDisplay = %q, want %q", got, want)
+	}
+}
+
+func TestPreviewDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "navtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readPreview(dir)
+	if len(lines) != 2 || lines[0] != "a.txt" || lines[1] != "b.txt" {
+		t.Errorf("readPreview(dir) = %v, want sorted [a.txt b.txt]", lines)
+	}
+}
+
+func TestNavigationPreviewCachesUntilSelectionChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "navtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "f.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	nav := &navigation{current: &navColumn{path: dir, names: []string{"f.txt"}, selected: 0}}
+	first := nav.preview()
+	if len(first) == 0 || first[0] != "hello" {
+		t.Fatalf("preview() = %v, want [hello]", first)
+	}
+
+	cachedFor := nav.previewFor
+	second := nav.preview()
+	if nav.previewFor != cachedFor {
+		t.Errorf("preview() recomputed although selection did not change")
+	}
+	if len(second) != len(first) || second[0] != first[0] {
+		t.Errorf("preview() = %v, want cached %v", second, first)
+	}
+}
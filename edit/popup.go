@@ -0,0 +1,137 @@
+package edit
+
+import "strings"
+
+// Values of the "edit:completion-style" variable (wired up by the command
+// layer), selecting which of the two completion listing layouts refresh
+// draws: the classic multi-column bufListing grid, or a floating popup
+// box anchored at the cursor.
+const (
+	completionStyleGrid  = "grid"
+	completionStylePopup = "popup"
+)
+
+// popupDetailGap separates a candidate's text from its optional detail and
+// doc columns inside a popup box.
+const popupDetailGap = 2
+
+// buildCompletionPopup lays out comp's candidates as a single-column,
+// bordered popup box: one candidate per row, in from the left edge its
+// text, then (if any candidate has one) a detail column, then (if any
+// candidate has one) a doc column, all box-drawn and clipped to maxWidth
+// columns and maxHeight rows. Unlike the grid, which reshapes the
+// candidate list into columns to fill the available width, a popup keeps
+// one candidate per row and scrolls by row, via the same findWindow
+// logic the grid uses for its rows.
+func buildCompletionPopup(comp *completion, maxWidth, maxHeight int) *buffer {
+	cands := comp.candidates
+	if len(cands) == 0 || maxWidth < 3 || maxHeight < 3 {
+		return nil
+	}
+
+	textWidth, detailWidth, docWidth := 0, 0, 0
+	for _, cand := range cands {
+		if w := wcwidths(cand.text); w > textWidth {
+			textWidth = w
+		}
+		if w := wcwidths(cand.detail); w > detailWidth {
+			detailWidth = w
+		}
+		if w := wcwidths(cand.doc); w > docWidth {
+			docWidth = w
+		}
+	}
+
+	// needed is how much width the text/detail/doc columns (plus their
+	// gaps) actually want; if that's more than fits in maxWidth, drop the
+	// doc column first, then detail, then clip textWidth itself, so the
+	// widths used below to trim and pad each row always agree with inner
+	// -- the box never wraps its border across extra lines.
+	avail := maxWidth - 2
+	if avail < 1 {
+		avail = 1
+	}
+	needed := func() int {
+		n := textWidth
+		if detailWidth > 0 {
+			n += popupDetailGap + detailWidth
+		}
+		if docWidth > 0 {
+			n += popupDetailGap + docWidth
+		}
+		return n
+	}
+	if needed() > avail && docWidth > 0 {
+		docWidth = 0
+	}
+	if needed() > avail && detailWidth > 0 {
+		detailWidth = 0
+	}
+	if needed() > avail {
+		textWidth = avail
+	}
+	inner := needed()
+
+	rowsAvail := maxHeight - 2
+	low, high := findWindow(len(cands), comp.current, rowsAvail)
+
+	b := newBuffer(inner + 2)
+	b.writes("┌", attrForPopupBorder)
+	b.writes(strings.Repeat("─", inner), attrForPopupBorder)
+	b.writes("┐", attrForPopupBorder)
+	for i := low; i < high; i++ {
+		b.newline()
+		cand := cands[i]
+		attr := ""
+		if i == comp.current {
+			attr = attrForCurrentCompletion
+		}
+		b.writes("│", attrForPopupBorder)
+		// Pad relative to the trimmed text, not cand.text itself: once
+		// textWidth/detailWidth/docWidth have been clamped to fit maxWidth,
+		// an untrimmed candidate can be wider than its column, which would
+		// turn this into a negative (panicking) padding count.
+		text := trimWcwidth(cand.text, textWidth)
+		b.writes(text, attr)
+		b.writePadding(textWidth-wcwidths(text), attr)
+		if detailWidth > 0 {
+			detail := trimWcwidth(cand.detail, detailWidth)
+			b.writePadding(popupDetailGap, attr)
+			b.writes(detail, attr)
+			b.writePadding(detailWidth-wcwidths(detail), attr)
+		}
+		if docWidth > 0 {
+			doc := trimWcwidth(cand.doc, docWidth)
+			b.writePadding(popupDetailGap, attr)
+			b.writes(doc, attr)
+			b.writePadding(docWidth-wcwidths(doc), attr)
+		}
+		b.writes("│", attrForPopupBorder)
+	}
+	b.newline()
+	b.writes("└", attrForPopupBorder)
+	b.writes(strings.Repeat("─", inner), attrForPopupBorder)
+	b.writes("┘", attrForPopupBorder)
+
+	return b
+}
+
+// popupOrigin returns where a popup box of the given width should be
+// anchored given the cursor position dot and the available width/height:
+// on the row below dot, shifted left as needed so the box fits within
+// width, with that row clamped to the last of the height rows. It does not
+// know the box's height; refresh sizes the box to the rows below dot.
+func popupOrigin(dot pos, boxWidth, width, height int) pos {
+	col := dot.col
+	if col+boxWidth > width {
+		col = width - boxWidth
+	}
+	if col < 0 {
+		col = 0
+	}
+	line := dot.line + 1
+	if line >= height {
+		line = height - 1
+	}
+	return pos{line, col}
+}
@@ -0,0 +1,79 @@
+package edit
+
+import "testing"
+
+func TestPopupOrigin(t *testing.T) {
+	// Plenty of room: box opens directly below and at the cursor's column.
+	if got := popupOrigin(pos{2, 5}, 10, 40, 20); got != (pos{3, 5}) {
+		t.Errorf("popupOrigin = %v, want {3 5}", got)
+	}
+}
+
+func TestPopupOriginClipsToWidth(t *testing.T) {
+	// Box would run off the right edge; it shifts left to stay on-screen.
+	got := popupOrigin(pos{0, 35}, 10, 40, 20)
+	if got.col+10 > 40 {
+		t.Errorf("popupOrigin col %d lets box run past width 40", got.col)
+	}
+}
+
+func TestPopupOriginClipsToHeight(t *testing.T) {
+	got := popupOrigin(pos{19, 0}, 10, 40, 20)
+	if got.line >= 20 {
+		t.Errorf("popupOrigin line %d is not clipped to height 20", got.line)
+	}
+}
+
+func TestBuildCompletionPopupNil(t *testing.T) {
+	comp := &completion{candidates: nil}
+	if b := buildCompletionPopup(comp, 40, 10); b != nil {
+		t.Errorf("buildCompletionPopup with no candidates = %v, want nil", b)
+	}
+}
+
+func TestBuildCompletionPopupLayout(t *testing.T) {
+	comp := &completion{
+		current: 1,
+		candidates: []candidate{
+			{text: "foo"},
+			{text: "barbaz"},
+		},
+	}
+	b := buildCompletionPopup(comp, 40, 10)
+	if b == nil {
+		t.Fatal("buildCompletionPopup returned nil")
+	}
+	// 1 border row + 2 candidate rows + 1 border row.
+	if len(b.cells) != 4 {
+		t.Errorf("got %d rows, want 4", len(b.cells))
+	}
+}
+
+func TestBuildCompletionPopupClampsWideCandidate(t *testing.T) {
+	comp := &completion{
+		current: 0,
+		candidates: []candidate{
+			{text: "a very long candidate name that won't fit", detail: "detail", doc: "doc"},
+		},
+	}
+	const maxWidth = 12
+	b := buildCompletionPopup(comp, maxWidth, 10)
+	if b == nil {
+		t.Fatal("buildCompletionPopup returned nil")
+	}
+	if b.width > maxWidth {
+		t.Errorf("box width %d exceeds maxWidth %d", b.width, maxWidth)
+	}
+	// Every row -- in particular the candidate row, not just the borders --
+	// must fit within maxWidth too, or the border wraps across extra lines
+	// instead of being clipped.
+	for i, row := range b.cells {
+		if w := cellsWidth(row); w > maxWidth {
+			t.Errorf("row %d width %d exceeds maxWidth %d", i, w, maxWidth)
+		}
+	}
+	// 1 border row + 1 candidate row + 1 border row.
+	if len(b.cells) != 3 {
+		t.Errorf("got %d rows, want 3", len(b.cells))
+	}
+}
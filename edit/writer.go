@@ -3,7 +3,9 @@ package edit
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -25,6 +27,15 @@ type pos struct {
 	line, col int
 }
 
+// tip is one line of bs.tips: a message paired with the attr it should be
+// rendered with, so tips of different severity (e.g. an LSP error
+// diagnostic vs. an informational hint) can be told apart visually rather
+// than all sharing attrForTip.
+type tip struct {
+	text string
+	attr string
+}
+
 // buffer reflects a continuous range of lines on the terminal. The Unix
 // terminal API provides only awkward ways of querying the terminal buffer, so
 // we keep an internal reflection and do one-way synchronizations (buffer ->
@@ -111,6 +122,88 @@ func (b *buffer) cursor() pos {
 	return pos{len(b.cells) - 1, b.col}
 }
 
+// splitCellsAtColumn splits cells at column col into the portion occupying
+// columns [0, col) and the portion from col onward. cell indices don't
+// line up with columns once a width-2+ cell is involved, so col may land
+// in the middle of one; since a wide glyph can't be partially overwritten,
+// that cell is dropped and replaced with single-width blanks spanning its
+// original columns. If col is at or past the column width of cells, cells
+// is padded with blanks out to col and after is nil.
+func splitCellsAtColumn(cells []cell, col int) (before, after []cell) {
+	c := 0
+	for i, cl := range cells {
+		w := int(cl.width)
+		if c+w <= col {
+			c += w
+			continue
+		}
+		if c == col {
+			return cells[:i], cells[i:]
+		}
+		blanks := make([]cell, w)
+		for j := range blanks {
+			blanks[j] = cell{rune: ' ', width: 1}
+		}
+		before = append(append([]cell{}, cells[:i]...), blanks[:col-c]...)
+		return before, append(blanks[col-c:], cells[i+1:]...)
+	}
+	before = append([]cell{}, cells...)
+	for ; c < col; c++ {
+		before = append(before, cell{rune: ' ', width: 1})
+	}
+	return before, nil
+}
+
+// clipCellsToWidth returns the longest prefix of cells whose cumulative
+// column width fits within width, dropping (not splitting) a cell that
+// would straddle the cutoff.
+func clipCellsToWidth(cells []cell, width int) []cell {
+	c := 0
+	for i, cl := range cells {
+		if c+int(cl.width) > width {
+			return cells[:i]
+		}
+		c += int(cl.width)
+	}
+	return cells
+}
+
+// overlay splices rows -- a box of cells such as a floating completion
+// popup -- onto b with its top-left corner at origin, extending b with
+// blank lines if origin falls past its current end. origin.col and each
+// row's width are column positions, not cell indices -- cell is explicitly
+// "not necessarily 1 column wide", so splicing is done by walking b's
+// existing cells accumulating width (as cellsWidth/commonPrefixLen do)
+// rather than indexing directly into the cell slice by column number.
+// Columns at or past b.width are dropped. This reuses the same cell/attr
+// model commitBuffer already diffs and draws, rather than adding a second
+// rendering path for popups.
+func (b *buffer) overlay(origin pos, rows [][]cell) {
+	if origin.col >= b.width {
+		return
+	}
+	avail := b.width - origin.col
+	for i, row := range rows {
+		line := origin.line + i
+		if line < 0 {
+			continue
+		}
+		for line >= len(b.cells) {
+			b.cells = append(b.cells, make([]cell, 0, b.width))
+		}
+
+		fitted := clipCellsToWidth(row, avail)
+		before, rest := splitCellsAtColumn(b.cells[line], origin.col)
+		_, tail := splitCellsAtColumn(rest, cellsWidth(fitted))
+
+		dst := make([]cell, 0, len(before)+len(fitted)+len(tail))
+		dst = append(dst, before...)
+		dst = append(dst, fitted...)
+		dst = append(dst, tail...)
+		b.cells[line] = dst
+	}
+}
+
 func (b *buffer) trimToLines(low, high int) {
 	for i := 0; i < low; i++ {
 		b.cells[i] = nil
@@ -125,17 +218,86 @@ func (b *buffer) trimToLines(low, high int) {
 // writer is the part of an Editor responsible for keeping the status of and
 // updating the screen.
 type writer struct {
-	file   *os.File
-	oldBuf *buffer
+	// file is where the writer ultimately writes to. It is an *os.File in
+	// production; tests substitute a fake to capture and inspect the raw
+	// escape sequences.
+	file     io.Writer
+	fd       uintptr
+	oldBuf   *buffer
+	oldWidth int
+
+	// getWinsize queries the terminal size for a file descriptor. It is
+	// tty.GetWinsize in production; tests substitute a fake so refresh's
+	// bounded-height logic can be exercised without a real terminal.
+	getWinsize func(fd int) tty.Winsize
+
+	// maxHeight caps the number of rows the editor draws into, so it can be
+	// embedded below existing terminal output instead of owning the whole
+	// screen. 0 means unlimited (use the full terminal height), matching the
+	// zero value of editorState's corresponding field.
+	maxHeight int
 }
 
 func newWriter(f *os.File) *writer {
-	writer := &writer{file: f, oldBuf: newBuffer(0)}
+	writer := &writer{file: f, fd: f.Fd(), oldBuf: newBuffer(0), getWinsize: tty.GetWinsize}
 	return writer
 }
 
+// ParseHeight parses a height option as accepted by the "-height" CLI flag:
+// either an absolute line count ("20") or a percentage of the terminal
+// height ("40%"), and returns the resulting absolute line count.
+func ParseHeight(spec string, termHeight int) (int, error) {
+	if pct := strings.TrimSuffix(spec, "%"); pct != spec {
+		n, err := strconv.Atoi(pct)
+		if err != nil {
+			return 0, fmt.Errorf("invalid height %q: %v", spec, err)
+		}
+		return termHeight * n / 100, nil
+	}
+	n, err := strconv.Atoi(spec)
+	if err != nil {
+		return 0, fmt.Errorf("invalid height %q: %v", spec, err)
+	}
+	return n, nil
+}
+
+// setMaxHeight sets the bounded-height drawing region. It is called once at
+// startup from the value of the editor's "-height"/"height" configuration
+// (wired up in the CLI entry point), and 0 restores unlimited height.
+func (w *writer) setMaxHeight(max int) {
+	w.maxHeight = max
+}
+
+// reserveHeight scrolls the terminal up by n lines and moves the cursor back
+// up by the same amount, making room below the current cursor row for a
+// bounded-height editor without overwriting whatever was already on screen.
+func (w *writer) reserveHeight(n int) error {
+	if n <= 0 {
+		return nil
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString(strings.Repeat("\n", n))
+	fmt.Fprintf(buf, "\033[%dA", n)
+	_, err := w.file.Write(buf.Bytes())
+	return err
+}
+
+// releaseHeight moves the cursor below the region last drawn by refresh, so
+// that output written after the editor exits flows naturally below it
+// instead of overwriting the last frame.
+func (w *writer) releaseHeight() error {
+	buf := new(bytes.Buffer)
+	if delta := len(w.oldBuf.cells) - 1 - w.oldBuf.dot.line; delta > 0 {
+		fmt.Fprintf(buf, "\033[%dB", delta)
+	}
+	buf.WriteString("\n")
+	_, err := w.file.Write(buf.Bytes())
+	return err
+}
+
 // deltaPos calculates the escape sequence needed to move the cursor from one
-// position to another.
+// position to another. It assumes both positions lie on lines that have
+// already been written, i.e. it never emits "\n".
 func deltaPos(from, to pos) []byte {
 	buf := new(bytes.Buffer)
 	if from.line < to.line {
@@ -149,24 +311,54 @@ func deltaPos(from, to pos) []byte {
 	return buf.Bytes()
 }
 
-// commitBuffer updates the terminal display to reflect current buffer.
-// TODO Instead of erasing w.oldBuf entirely and then draw buf, compute a
-// delta between w.oldBuf and buf
-func (w *writer) commitBuffer(buf *buffer) error {
-	bytesBuf := new(bytes.Buffer)
+// cellsWidth returns the number of columns occupied by cells.
+func cellsWidth(cells []cell) int {
+	w := 0
+	for _, c := range cells {
+		w += int(c.width)
+	}
+	return w
+}
+
+// commonPrefixLen returns the length of the longest prefix of a and b whose
+// cells are identical in rune, width and attr.
+func commonPrefixLen(a, b []cell) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
 
-	pLine := w.oldBuf.dot.line
-	if pLine > 0 {
-		fmt.Fprintf(bytesBuf, "\033[%dA", pLine)
+// resetOldBuf discards the writer's record of what is currently on the
+// terminal, forcing the next commitBuffer to fully redraw every line. This
+// is needed whenever the terminal's own idea of the screen can no longer be
+// trusted to match oldBuf -- e.g. after a SIGWINCH, or when the window width
+// has changed and the old line wrapping no longer applies. It moves the
+// cursor back to the top of the last drawn region and erases from there
+// down, so the redraw starts on a blank region at the row it assumes.
+func (w *writer) resetOldBuf() {
+	if line := w.oldBuf.dot.line; line > 0 {
+		fmt.Fprintf(w.file, "\033[%dA", line)
 	}
-	bytesBuf.WriteString("\r\033[J")
+	io.WriteString(w.file, "\r\033[J")
+	w.oldBuf = newBuffer(0)
+}
+
+// commitBuffer updates the terminal display to reflect current buffer,
+// writing only what differs between w.oldBuf and buf: unchanged lines are
+// skipped, and a changed line is rewritten from its first differing cell
+// to its end. A full redraw can be forced by calling resetOldBuf
+// beforehand, since diffing against an empty buffer naturally rewrites
+// every line.
+func (w *writer) commitBuffer(buf *buffer) error {
+	bytesBuf := new(bytes.Buffer)
 
+	cursor := w.oldBuf.dot
 	attr := ""
-	for i, line := range buf.cells {
-		if i > 0 {
-			bytesBuf.WriteString("\n")
-		}
-		for _, c := range line {
+
+	writeCells := func(cells []cell) {
+		for _, c := range cells {
 			if c.width > 0 && c.attr != attr {
 				fmt.Fprintf(bytesBuf, "\033[m\033[%sm", c.attr)
 				attr = c.attr
@@ -174,14 +366,58 @@ func (w *writer) commitBuffer(buf *buffer) error {
 			bytesBuf.WriteString(string(c.rune))
 		}
 	}
+
+	moveTo := func(to pos) {
+		if to != cursor {
+			bytesBuf.Write(deltaPos(cursor, to))
+			cursor = to
+		}
+	}
+
+	oldLines, newLines := len(w.oldBuf.cells), len(buf.cells)
+
+	for i := 0; i < newLines; i++ {
+		if i > 0 && i >= oldLines {
+			// This is a brand new line past the end of the old buffer; it
+			// has to be created with a newline rather than a cursor move.
+			// cursor may still be sitting on an earlier row -- e.g. row i-1
+			// was unchanged and hit the "continue" below without moving it --
+			// so get it onto row i-1 first; the newline then reliably lands
+			// on row i rather than wherever row i-1 happened to be relative
+			// to cursor.
+			moveTo(pos{i - 1, cursor.col})
+			bytesBuf.WriteString("\n")
+			cursor = pos{i, 0}
+		}
+
+		newLine := buf.cells[i]
+		var oldLine []cell
+		if i < oldLines {
+			oldLine = w.oldBuf.cells[i]
+		}
+
+		prefix := commonPrefixLen(oldLine, newLine)
+		if prefix == len(oldLine) && prefix == len(newLine) {
+			// Line is unchanged; nothing to rewrite.
+			continue
+		}
+
+		moveTo(pos{i, cellsWidth(newLine[:prefix])})
+		writeCells(newLine[prefix:])
+		cursor = pos{i, cellsWidth(newLine)}
+		if len(newLine) < len(oldLine) {
+			bytesBuf.WriteString("\033[K")
+		}
+	}
+	if newLines < oldLines {
+		// The old buffer had extra lines at the bottom; drop them.
+		moveTo(pos{newLines, 0})
+		bytesBuf.WriteString("\033[J")
+	}
 	if attr != "" {
 		bytesBuf.WriteString("\033[m")
 	}
-	cursor := buf.cursor()
-	if cursor.col == buf.width {
-		cursor.col--
-	}
-	bytesBuf.Write(deltaPos(cursor, buf.dot))
+	moveTo(buf.dot)
 
 	_, err := w.file.Write(bytesBuf.Bytes())
 	if err != nil {
@@ -231,8 +467,21 @@ func trimToWindow(s []string, selected, max int) ([]string, int) {
 // refresh redraws the line editor. The dot is passed as an index into text;
 // the corresponding position will be calculated.
 func (w *writer) refresh(bs *editorState) error {
-	winsize := tty.GetWinsize(int(w.file.Fd()))
+	winsize := w.getWinsize(int(w.fd))
 	width, height := int(winsize.Col), int(winsize.Row)
+	if w.maxHeight > 0 && w.maxHeight < height {
+		// Draw into a region no taller than maxHeight, leaving the rest of
+		// the terminal (and whatever was already printed above it) alone.
+		height = w.maxHeight
+	}
+
+	if width != w.oldWidth {
+		// The old buffer was laid out for a different width, so its line
+		// wrapping no longer lines up with reality; fall back to a full
+		// redraw rather than trying to diff against it.
+		w.resetOldBuf()
+		w.oldWidth = width
+	}
 
 	var bufLine, bufMode, bufTips, bufListing, buf *buffer
 	// bufLine
@@ -327,7 +576,22 @@ tokens:
 	if len(bs.tips) > 0 {
 		b := newBuffer(width)
 		bufTips = b
-		b.writes(trimWcwidth(strings.Join(bs.tips, ", "), width), attrForTip)
+		remaining := width
+		for i, t := range bs.tips {
+			if i > 0 {
+				if remaining < 2 {
+					break
+				}
+				b.writes(", ", attrForTip)
+				remaining -= 2
+			}
+			text := trimWcwidth(t.text, remaining)
+			b.writes(text, t.attr)
+			remaining -= wcwidths(text)
+			if remaining <= 0 {
+				break
+			}
+		}
 	}
 
 	listingHeight := 0
@@ -353,7 +617,11 @@ tokens:
 		b := newBuffer(width)
 		bufListing = b
 		// Completion listing
-		if comp != nil {
+		if comp != nil && bs.completionStyle == completionStylePopup {
+			// Drawn as a floating popup overlaid on buf below, once bufLine,
+			// bufMode and bufTips are all in their final positions.
+			bufListing = nil
+		} else if comp != nil {
 			// Layout candidates in multiple columns
 			cands := comp.candidates
 
@@ -404,15 +672,31 @@ tokens:
 
 			filenames, low := trimToWindow(nav.current.names, nav.current.selected, listingHeight)
 			parentFilenames, parentLow := trimToWindow(nav.parent.names, nav.parent.selected, listingHeight)
+			previewLines := nav.preview()
+			if len(previewLines) > listingHeight {
+				previewLines = previewLines[:listingHeight]
+			}
 
-			// TODO(xiaq): When laying out the navigation listing, determine
-			// the width of two columns more intelligently instead of
-			// allocating half of screen for each. Maybe the algorithm used by
-			// ranger could be pirated.
+			// Ratio-driven column layout, ranger-style: parent/current/
+			// preview split the available width 1:2:3, after every column
+			// is first given enough room for the longest parent/current
+			// filename (clamped, so one very long name doesn't dominate the
+			// layout) when the width allows it; see navColumnWidths.
 			colMargin := 1
-			parentWidth := (width + colMargin) / 2
-			currentWidth := width - colMargin - parentWidth
-			for i := 0; i < len(filenames) || i < len(parentFilenames); i++ {
+			ratios := []int{1, 2, 3}
+			minWidth := longestName(navMaxMinColWidth, nav.parent, nav.current)
+			avail := width - colMargin*(len(ratios)-1)
+			colWidths := navColumnWidths(ratios, avail, minWidth)
+			parentWidth, currentWidth, previewWidth := colWidths[0], colWidths[1], colWidths[2]
+
+			rows := len(filenames)
+			if len(parentFilenames) > rows {
+				rows = len(parentFilenames)
+			}
+			if len(previewLines) > rows {
+				rows = len(previewLines)
+			}
+			for i := 0; i < rows; i++ {
 				if i > 0 {
 					b.newline()
 				}
@@ -427,14 +711,21 @@ tokens:
 				b.writePadding(parentWidth-wcwidths(text), attr)
 				b.writePadding(colMargin, "")
 
+				text, attr = "", ""
 				if i < len(filenames) {
-					attr := ""
-					if i+low == nav.current.selected {
-						attr = attrForSelectedFile
-					}
-					text := filenames[i]
-					b.writes(trimWcwidth(text, currentWidth), attr)
-					b.writePadding(currentWidth-wcwidths(text), attr)
+					text = filenames[i]
+				}
+				if i+low == nav.current.selected {
+					attr = attrForSelectedFile
+				}
+				b.writes(trimWcwidth(text, currentWidth), attr)
+				b.writePadding(currentWidth-wcwidths(text), attr)
+				b.writePadding(colMargin, "")
+
+				if i < len(previewLines) {
+					text = previewLines[i]
+					b.writes(trimWcwidth(text, previewWidth), "")
+					b.writePadding(previewWidth-wcwidths(text), "")
 				}
 			}
 		}
@@ -449,5 +740,12 @@ tokens:
 	buf.extend(bufTips)
 	buf.extend(bufListing)
 
+	if comp != nil && bs.completionStyle == completionStylePopup {
+		// The box opens on the row below dot, so it gets the rows below it.
+		if popup := buildCompletionPopup(comp, width, height-buf.dot.line-1); popup != nil {
+			buf.overlay(popupOrigin(buf.dot, popup.width, width, height), popup.cells)
+		}
+	}
+
 	return w.commitBuffer(buf)
 }
@@ -0,0 +1,355 @@
+package edit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xiaq/elvish/edit/tty"
+)
+
+// fakeFile is a fake os.File standing in for the real terminal in tests,
+// capturing whatever the writer would have sent to it.
+type fakeFile struct {
+	bytes.Buffer
+}
+
+// drain returns everything written so far and resets the buffer.
+func (f *fakeFile) drain() []byte {
+	b := append([]byte(nil), f.Bytes()...)
+	f.Reset()
+	return b
+}
+
+func newFakeWriter() (*writer, *fakeFile) {
+	f := &fakeFile{}
+	return &writer{
+		file:       f,
+		oldBuf:     newBuffer(0),
+		getWinsize: func(int) tty.Winsize { return tty.Winsize{Col: 80, Row: 24} },
+	}, f
+}
+
+func bufFromLines(width int, lines []string, attr string) *buffer {
+	b := newBuffer(width)
+	for i, line := range lines {
+		if i > 0 {
+			b.newline()
+		}
+		b.writes(line, attr)
+	}
+	return b
+}
+
+func cellsEqual(a, b *buffer) bool {
+	if len(a.cells) != len(b.cells) {
+		return false
+	}
+	for i := range a.cells {
+		if len(a.cells[i]) != len(b.cells[i]) {
+			return false
+		}
+		for j := range a.cells[i] {
+			if a.cells[i][j] != b.cells[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestCommitBufferCellEquivalence(t *testing.T) {
+	w, _ := newFakeWriter()
+
+	bufs := []*buffer{
+		bufFromLines(20, []string{"hello world"}, ""),
+		bufFromLines(20, []string{"hello there"}, ""),
+		bufFromLines(20, []string{"hello there", "second line"}, ""),
+		bufFromLines(20, []string{"hello", "second line"}, ""),
+	}
+	for _, buf := range bufs {
+		if err := w.commitBuffer(buf); err != nil {
+			t.Fatal(err)
+		}
+		if !cellsEqual(w.oldBuf, buf) {
+			t.Errorf("oldBuf not in sync with committed buffer %v", buf.cells)
+		}
+	}
+}
+
+func TestCommitBufferMinimalOutput(t *testing.T) {
+	w, f := newFakeWriter()
+
+	full := bufFromLines(20, []string{"0123456789abcdefghij"}, "")
+	if err := w.commitBuffer(full); err != nil {
+		t.Fatal(err)
+	}
+	fullBytes := f.drain()
+
+	// Changing a single character should need fewer bytes than a full
+	// redraw of the line, since only the tail from that character on is
+	// rewritten.
+	tweaked := bufFromLines(20, []string{"0123456789Xbcdefghij"}, "")
+	if err := w.commitBuffer(tweaked); err != nil {
+		t.Fatal(err)
+	}
+	deltaBytes := f.drain()
+
+	if len(deltaBytes) >= len(fullBytes) {
+		t.Errorf("delta commit wrote %d bytes, want fewer than full commit's %d", len(deltaBytes), len(fullBytes))
+	}
+	if !cellsEqual(w.oldBuf, tweaked) {
+		t.Errorf("oldBuf not in sync after delta commit")
+	}
+}
+
+func TestParseHeight(t *testing.T) {
+	cases := []struct {
+		spec       string
+		termHeight int
+		want       int
+	}{
+		{"20", 100, 20},
+		{"40%", 100, 40},
+		{"50%", 41, 20},
+	}
+	for _, c := range cases {
+		got, err := ParseHeight(c.spec, c.termHeight)
+		if err != nil {
+			t.Errorf("ParseHeight(%q, %d) returned error: %v", c.spec, c.termHeight, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseHeight(%q, %d) = %d, want %d", c.spec, c.termHeight, got, c.want)
+		}
+	}
+	if _, err := ParseHeight("bogus", 100); err == nil {
+		t.Errorf("ParseHeight(\"bogus\", 100) expected an error, got nil")
+	}
+}
+
+func TestBufferOverlay(t *testing.T) {
+	b := bufFromLines(10, []string{"0123456789"}, "")
+	box := bufFromLines(4, []string{"ab", "cd"}, "")
+
+	b.overlay(pos{0, 2}, box.cells)
+
+	got := string(cellsText(b.cells[0]))
+	if want := "01ab456789"; got != want {
+		t.Errorf("overlay row 0 = %q, want %q", got, want)
+	}
+	if len(b.cells) != 2 {
+		t.Fatalf("overlay did not extend buffer to 2 lines, got %d", len(b.cells))
+	}
+	got = string(cellsText(b.cells[1]))
+	if want := "  cd"; got != want {
+		t.Errorf("overlay row 1 = %q, want %q", got, want)
+	}
+}
+
+func TestBufferOverlayDropsColumnsPastWidth(t *testing.T) {
+	b := bufFromLines(4, []string{"aaaa"}, "")
+	box := bufFromLines(4, []string{"XXXX"}, "")
+
+	b.overlay(pos{0, 2}, box.cells)
+
+	got := string(cellsText(b.cells[0]))
+	if want := "aaXX"; got != want {
+		t.Errorf("overlay = %q, want %q", got, want)
+	}
+}
+
+func TestBufferOverlaySplitsWideCellAtOrigin(t *testing.T) {
+	// "中" is 2 columns wide, so the line below is 5 cells spanning 6
+	// columns: 中(cols 0-1) a(2) b(3) c(4) d(5). Anchoring the overlay at
+	// column 1 lands in the middle of 中 -- a column-index bug would
+	// instead treat column 1 as cell index 1 ("a") and corrupt the line.
+	b := bufFromLines(10, []string{"中abcd"}, "")
+	box := bufFromLines(10, []string{"XY"}, "")
+
+	b.overlay(pos{0, 1}, box.cells)
+
+	got := string(cellsText(b.cells[0]))
+	if want := " XYbcd"; got != want {
+		t.Errorf("overlay = %q, want %q", got, want)
+	}
+}
+
+func cellsText(cells []cell) []rune {
+	rs := make([]rune, len(cells))
+	for i, c := range cells {
+		rs[i] = c.rune
+	}
+	return rs
+}
+
+// termEmulator replays the subset of escape sequences commitBuffer emits
+// (cursor up/down, absolute column, erase-to-eol, erase-below, "\n" as a
+// terminal would with ONLCR) against a grid of lines, so a test can
+// assert on what ends up on screen rather than just on the byte stream.
+type termEmulator struct {
+	lines    []string
+	row, col int
+}
+
+func (e *termEmulator) apply(b []byte) {
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case '\n':
+			e.row++
+			e.col = 0
+		case 0x1b: // ESC '[' ... final-byte
+			j := i + 2
+			for j < len(b) && (b[j] < '@' || b[j] > '~') {
+				j++
+			}
+			n := 0
+			hasN := j > i+2
+			if hasN {
+				for _, d := range b[i+2 : j] {
+					n = n*10 + int(d-'0')
+				}
+			}
+			switch b[j] {
+			case 'A':
+				e.row -= n
+			case 'B':
+				e.row += n
+			case 'G':
+				e.col = n - 1
+			case 'K':
+				e.setLine(e.row, e.line(e.row)[:e.col])
+			case 'J':
+				e.lines = e.lines[:e.row+1]
+			case 'm':
+				// Attribute changes don't affect the visible text grid.
+			}
+			i = j
+		default:
+			e.writeRune(rune(b[i]))
+		}
+	}
+}
+
+func (e *termEmulator) line(row int) string {
+	for len(e.lines) <= row {
+		e.lines = append(e.lines, "")
+	}
+	return e.lines[row]
+}
+
+func (e *termEmulator) setLine(row int, s string) {
+	e.line(row)
+	e.lines[row] = s
+}
+
+func (e *termEmulator) writeRune(r rune) {
+	line := []rune(e.line(e.row))
+	for len(line) <= e.col {
+		line = append(line, ' ')
+	}
+	line[e.col] = r
+	e.setLine(e.row, string(line))
+	e.col++
+}
+
+func TestCommitBufferUnchangedLineThenAppendedLine(t *testing.T) {
+	w, f := newFakeWriter()
+	term := &termEmulator{}
+
+	first := bufFromLines(20, []string{"prompt line", "listing row B"}, "")
+	if err := w.commitBuffer(first); err != nil {
+		t.Fatal(err)
+	}
+	term.apply(f.drain())
+
+	// Both existing lines are unchanged; only a third, brand-new line is
+	// appended. Row 0 and row 1 never move the tracked cursor, so the
+	// "\n" that creates row 2 must not assume the cursor is already
+	// sitting on row 1.
+	second := bufFromLines(20, []string{"prompt line", "listing row B", "listing row C"}, "")
+	if err := w.commitBuffer(second); err != nil {
+		t.Fatal(err)
+	}
+	term.apply(f.drain())
+
+	want := []string{"prompt line", "listing row B", "listing row C"}
+	for i, line := range want {
+		if i >= len(term.lines) || term.lines[i] != line {
+			t.Errorf("line %d = %q, want %q (screen: %v)", i, term.line(i), line, term.lines)
+		}
+	}
+}
+
+func TestReserveHeight(t *testing.T) {
+	w, f := newFakeWriter()
+
+	if err := w.reserveHeight(2); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(f.drain()), "\n\n\033[2A"; got != want {
+		t.Errorf("reserveHeight(2) wrote %q, want %q", got, want)
+	}
+
+	if err := w.reserveHeight(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.drain(); len(got) != 0 {
+		t.Errorf("reserveHeight(0) wrote %q, want nothing", got)
+	}
+}
+
+func TestReleaseHeight(t *testing.T) {
+	w, f := newFakeWriter()
+
+	// Cursor is on the line above the last drawn line: releaseHeight must
+	// move down the remaining distance before its own trailing "\n".
+	w.oldBuf = &buffer{cells: [][]cell{{}, {}, {}}, dot: pos{line: 0}}
+	if err := w.releaseHeight(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(f.drain()), "\033[2B\n"; got != want {
+		t.Errorf("releaseHeight() wrote %q, want %q", got, want)
+	}
+
+	// Cursor is already on the last drawn line: no cursor movement needed,
+	// just the trailing "\n".
+	w.oldBuf = &buffer{cells: [][]cell{{}, {}, {}}, dot: pos{line: 2}}
+	if err := w.releaseHeight(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(f.drain()), "\n"; got != want {
+		t.Errorf("releaseHeight() wrote %q, want %q", got, want)
+	}
+}
+
+func TestRefreshCapsDrawnRegionToMaxHeight(t *testing.T) {
+	w, _ := newFakeWriter()
+	w.getWinsize = func(int) tty.Winsize { return tty.Winsize{Col: 40, Row: 24} }
+	w.setMaxHeight(3)
+
+	bs := &editorState{prompt: "> ", mode: modeInsert}
+	if err := w.refresh(bs); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(w.oldBuf.cells); got > 3 {
+		t.Errorf("refresh with maxHeight=3 drew %d lines, want <= 3", got)
+	}
+}
+
+func TestResetOldBufForcesFullRedraw(t *testing.T) {
+	w, f := newFakeWriter()
+
+	buf := bufFromLines(20, []string{"same line"}, "")
+	if err := w.commitBuffer(buf); err != nil {
+		t.Fatal(err)
+	}
+	f.drain()
+
+	w.resetOldBuf()
+	if err := w.commitBuffer(buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.drain()) == 0 {
+		t.Errorf("expected resetOldBuf to force a non-empty redraw even though the buffer did not change")
+	}
+}
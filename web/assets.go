@@ -0,0 +1,11 @@
+package web
+
+//go:generate go run -tags=dev assets_generate.go
+
+// Assets is the virtual filesystem backing the web UI's static files:
+// index.html, main.css and main.js. Built normally it's
+// assets_vfsdata.go, generated ahead of time by the go:generate
+// directive above so the binary doesn't depend on the source tree at
+// runtime. Built with -tags=dev (see assets_dev.go) it instead reads
+// straight from the assets/ directory on disk, so editing HTML/CSS/JS
+// takes effect on the next reload with no regeneration step.
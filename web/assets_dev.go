@@ -0,0 +1,9 @@
+//go:build dev
+// +build dev
+
+package web
+
+import "net/http"
+
+// Assets reads straight off disk in dev builds; see assets.go.
+var Assets http.FileSystem = http.Dir("assets")
@@ -0,0 +1,26 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/shurcooL/vfsgen"
+)
+
+// assets_generate.go is run via assets.go's go:generate directive to
+// produce assets_vfsdata.go, embedding the assets/ directory into the
+// binary so a normal build doesn't depend on the source tree at runtime.
+func main() {
+	err := vfsgen.Generate(http.Dir("../assets"), vfsgen.Options{
+		Filename:     "assets_vfsdata.go",
+		PackageName:  "web",
+		BuildTags:    "!dev",
+		VariableName: "Assets",
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"html/template"
+	"io/ioutil"
+	"net/http"
+)
+
+// Version is reported to the page as part of ELVISH_CONFIG. The command
+// that starts the web server is expected to set it before serving any
+// requests; left unset it just reports "unknown".
+var Version = "unknown"
+
+// pageConfig is exposed to index.html as ELVISH_CONFIG, telling the
+// static JS where to open its stream and what build of elvish it's
+// talking to.
+type pageConfig struct {
+	StreamURL string
+	Version   string
+}
+
+// pageHandler serves the templated index.html for "/", and the rest of
+// Assets verbatim for "/static/".
+type pageHandler struct {
+	tpl *template.Template
+}
+
+// newPageHandler parses index.html out of assets for templating. It
+// errors if assets doesn't have an index.html, e.g. a stale dev checkout.
+func newPageHandler() (*pageHandler, error) {
+	f, err := Assets.Open("/index.html")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	content, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := template.New("index.html").Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	return &pageHandler{tpl: tpl}, nil
+}
+
+func (h *pageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	h.tpl.Execute(w, pageConfig{StreamURL: "/stream", Version: Version})
+}
+
+// RegisterAssetRoutes wires the templated index page and the rest of
+// Assets (main.css, main.js, ...) as static files under /static/ into
+// mux, so the caller just needs to add the evaluation endpoints.
+func RegisterAssetRoutes(mux *http.ServeMux) error {
+	page, err := newPageHandler()
+	if err != nil {
+		return err
+	}
+	mux.Handle("/", page)
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(Assets)))
+	return nil
+}
@@ -0,0 +1,54 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPageHandlerTemplatesConfig(t *testing.T) {
+	h, err := newPageHandler()
+	if err != nil {
+		t.Fatalf("newPageHandler: %v", err)
+	}
+
+	old := Version
+	Version = "1.2.3"
+	defer func() { Version = old }()
+
+	req := httptest.NewRequest("GET", "/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `"1.2.3"`) {
+		t.Errorf("rendered page missing version, got %q", body)
+	}
+	// html/template contextually escapes "/" within a <script> block to
+	// guard against a literal "</script>" breaking out of the tag.
+	if !strings.Contains(body, `streamURL`) || !strings.Contains(body, `stream`) {
+		t.Errorf("rendered page missing stream URL, got %q", body)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html", ct)
+	}
+}
+
+func TestRegisterAssetRoutesServesStaticFiles(t *testing.T) {
+	mux := http.NewServeMux()
+	if err := RegisterAssetRoutes(mux); err != nil {
+		t.Fatalf("RegisterAssetRoutes: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/static/main.css", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Fatalf("GET /static/main.css = %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "#code") {
+		t.Errorf("main.css body missing expected rule, got %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,199 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/xiaq/elvish/eval"
+)
+
+// Evaler is the subset of eval's top-level evaluator the web server
+// depends on: evaluate code against the given stdout/stderr, pushing
+// each Value the code emits onto values, and return promptly once ctx is
+// cancelled.
+type Evaler interface {
+	Eval(ctx context.Context, code string, stdout, stderr io.Writer, values chan<- eval.Value) error
+}
+
+// streamEvent is one Server-Sent Event pushed to the browser while code
+// is still running.
+type streamEvent struct {
+	kind string
+	data string
+}
+
+// writeSSE writes ev in the wire format EventSource expects, one "data:"
+// line per line of ev.data so multi-line output survives the framing.
+func writeSSE(w io.Writer, ev streamEvent) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", ev.kind)
+	for _, line := range strings.Split(ev.data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// streamWriter is an io.Writer that turns each Write into a streamEvent of
+// the given kind, for use as one of the evaluator's output ports. ctx is
+// the request's context, so a Write blocked on the unbuffered events
+// channel unblocks as soon as the client goes away instead of leaking for
+// the life of the process.
+type streamWriter struct {
+	kind   string
+	events chan<- streamEvent
+	ctx    context.Context
+}
+
+func (w streamWriter) Write(p []byte) (int, error) {
+	select {
+	case w.events <- streamEvent{kind: w.kind, data: string(p)}:
+		return len(p), nil
+	case <-w.ctx.Done():
+		return 0, w.ctx.Err()
+	}
+}
+
+// streamValues relays each Value received on values, the evaluator's
+// OutValues channel, as an "outvalue" event, until values is closed or
+// ctx is cancelled (again, so a send blocked on events can't outlive a
+// departed client).
+func streamValues(ctx context.Context, values <-chan eval.Value, events chan<- streamEvent) {
+	for v := range values {
+		select {
+		case events <- streamEvent{kind: "outvalue", data: v.Repr()}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cancelRegistry tracks the cancel funcs of in-flight /stream evaluations,
+// keyed by the id the client generated when opening the stream, so a
+// later POST to /stream/cancel can abort the right one.
+type cancelRegistry struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newCancelRegistry() *cancelRegistry {
+	return &cancelRegistry{cancels: make(map[string]context.CancelFunc)}
+}
+
+func (r *cancelRegistry) put(id string, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancels[id] = cancel
+}
+
+func (r *cancelRegistry) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cancels, id)
+}
+
+func (r *cancelRegistry) cancel(id string) {
+	r.mu.Lock()
+	cancel, ok := r.cancels[id]
+	delete(r.cancels, id)
+	r.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// streamHandler serves /stream, upgrading to text/event-stream and
+// evaluating the request as code, and /stream/cancel, which aborts a
+// stream identified by its "id" query parameter (the server side of
+// Ctrl-C in the browser).
+type streamHandler struct {
+	evaler  Evaler
+	cancels *cancelRegistry
+}
+
+func newStreamHandler(evaler Evaler) *streamHandler {
+	return &streamHandler{evaler: evaler, cancels: newCancelRegistry()}
+}
+
+// ServeHTTP evaluates the code carried by the request (the body for a
+// POST, the "code" query parameter for the GET an EventSource makes) and
+// streams out/err/outvalue events as the evaluator produces them,
+// flushing after every event so the browser sees them progressively,
+// finishing with "exception" (if the evaluation errored) and then "done".
+func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" && r.Method == http.MethodPost {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		code = string(body)
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	if id := r.URL.Query().Get("id"); id != "" {
+		h.cancels.put(id, cancel)
+		defer h.cancels.remove(id)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events := make(chan streamEvent)
+	values := make(chan eval.Value)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(values)
+		errc <- h.evaler.Eval(ctx, code,
+			streamWriter{kind: "out", events: events, ctx: ctx},
+			streamWriter{kind: "err", events: events, ctx: ctx}, values)
+	}()
+	go func() {
+		// streamValues returns once values is closed (i.e. once Eval has
+		// returned and errc already holds its result, so the <-errc below
+		// never blocks) or once ctx is cancelled, whichever comes first.
+		streamValues(ctx, values, events)
+		if err := <-errc; err != nil {
+			select {
+			case events <- streamEvent{kind: "exception", data: err.Error()}:
+			case <-ctx.Done():
+			}
+		}
+		select {
+		case events <- streamEvent{kind: "done"}:
+		case <-ctx.Done():
+		}
+		close(events)
+	}()
+
+	for ev := range events {
+		if err := writeSSE(w, ev); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// cancel handles /stream/cancel, aborting the evaluation registered under
+// the "id" query parameter.
+func (h *streamHandler) cancel(w http.ResponseWriter, r *http.Request) {
+	h.cancels.cancel(r.URL.Query().Get("id"))
+}
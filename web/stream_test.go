@@ -0,0 +1,147 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xiaq/elvish/eval"
+)
+
+func TestWriteSSE(t *testing.T) {
+	var b strings.Builder
+	err := writeSSE(&b, streamEvent{kind: "out", data: "line one\nline two"})
+	if err != nil {
+		t.Fatalf("writeSSE: %v", err)
+	}
+	want := "event: out\ndata: line one\ndata: line two\n\n"
+	if b.String() != want {
+		t.Errorf("writeSSE wrote %q, want %q", b.String(), want)
+	}
+}
+
+// fakeValue is a minimal eval.Value for tests.
+type fakeValue string
+
+func (fakeValue) Kind() string   { return "string" }
+func (v fakeValue) Repr() string { return fmt.Sprintf("%q", string(v)) }
+
+// fakeEvaler writes fixed output/values and returns a fixed error,
+// standing in for eval's real evaluator.
+type fakeEvaler struct {
+	out, err string
+	values   []eval.Value
+	retErr   error
+}
+
+func (f *fakeEvaler) Eval(ctx context.Context, code string, stdout, stderr io.Writer, values chan<- eval.Value) error {
+	io.WriteString(stdout, f.out)
+	io.WriteString(stderr, f.err)
+	for _, v := range f.values {
+		values <- v
+	}
+	return f.retErr
+}
+
+func TestStreamHandlerEmitsEventsInOrder(t *testing.T) {
+	h := newStreamHandler(&fakeEvaler{
+		out:    "hello\n",
+		err:    "oops\n",
+		values: []eval.Value{fakeValue("x")},
+		retErr: fmt.Errorf("boom"),
+	})
+
+	req := httptest.NewRequest("GET", "/stream?code=ignored", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"event: out\ndata: hello\n",
+		"event: err\ndata: oops\n",
+		"event: outvalue\ndata: \"x\"\n",
+		"event: exception\ndata: boom\n",
+		"event: done\ndata: \n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("response missing %q; got:\n%s", want, body)
+		}
+	}
+	if strings.Index(body, "event: out\n") > strings.Index(body, "event: exception\n") {
+		t.Errorf("exception event came before out event:\n%s", body)
+	}
+}
+
+// failOnWriteRecorder fails every body Write, as if the client had
+// disconnected mid-response, while still recording headers normally.
+// Both Write and WriteString need overriding: writeSSE writes via
+// io.WriteString, which bypasses an embedded *httptest.ResponseRecorder's
+// Write and calls its promoted WriteString (which always succeeds)
+// unless WriteString is overridden too.
+type failOnWriteRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (w *failOnWriteRecorder) Write(p []byte) (int, error) {
+	return 0, fmt.Errorf("client gone")
+}
+
+func (w *failOnWriteRecorder) WriteString(s string) (int, error) {
+	return 0, fmt.Errorf("client gone")
+}
+
+// blockingEvaler writes two chunks of output, closing done after the
+// second Write returns (successfully or not). Only the first chunk is
+// read by ServeHTTP's event loop before the client's write error makes
+// ServeHTTP give up on the stream; the second chunk's Write is left
+// stranded with nothing left to read the unbuffered events channel.
+type blockingEvaler struct {
+	done chan struct{}
+}
+
+func (e *blockingEvaler) Eval(ctx context.Context, code string, stdout, stderr io.Writer, values chan<- eval.Value) error {
+	defer close(e.done)
+	if _, err := stdout.Write([]byte("first\n")); err != nil {
+		return err
+	}
+	_, err := stdout.Write([]byte("second\n"))
+	return err
+}
+
+func TestStreamWriterUnblocksWhenClientGoesAway(t *testing.T) {
+	e := &blockingEvaler{done: make(chan struct{})}
+	h := newStreamHandler(e)
+
+	req := httptest.NewRequest("GET", "/stream?code=ignored", nil)
+	rec := &failOnWriteRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(rec, req)
+
+	select {
+	case <-e.done:
+		// The stranded second Write returned once ServeHTTP cancelled the
+		// request context, instead of blocking on the events channel
+		// forever.
+	case <-time.After(time.Second):
+		t.Fatal("Eval's second Write never returned after the client disconnected; streamWriter leaked")
+	}
+}
+
+func TestCancelRegistry(t *testing.T) {
+	r := newCancelRegistry()
+	cancelled := false
+	r.put("id1", func() { cancelled = true })
+
+	r.cancel("id2") // unknown id: no-op
+	if cancelled {
+		t.Fatalf("cancelling unknown id ran id1's cancel func")
+	}
+
+	r.cancel("id1")
+	if !cancelled {
+		t.Errorf("cancel(id1) did not run its cancel func")
+	}
+}